@@ -4,26 +4,68 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"time"
 )
 
 const (
 	shareSnapshot = "sharesnapshot"
+
+	// SnapshotTimeFormat is the format that a share snapshot's ShareSnapshot field needs to be formatted with.
+	SnapshotTimeFormat = "2006-01-02T15:04:05.0000000Z07:00"
+
+	// secondaryHostSuffix is appended to the account name label of a host that points at the RA-GRS
+	// secondary endpoint, Ex: "myaccount-secondary.file.core.windows.net".
+	secondaryHostSuffix = "-secondary"
 )
 
 // A FileURLParts object represents the components that make up an Azure Storage Share/Directory/File URL. You parse an
 // existing URL into its parts by calling NewFileURLParts(). You construct a URL from parts by calling URL().
 // NOTE: Changing any SAS-related field requires computing a new SAS signature.
 type FileURLParts struct {
-	Scheme              string // Ex: "https://"
-	Host                string // Ex: "account.share.core.windows.net"
-	ShareName           string // Share name, Ex: "myshare"
-	DirectoryOrFilePath string // Path of directory or file, Ex: "mydirectory/myfile"
-	ShareSnapshot       string // IsZero is true if not a snapshot
-	SAS                 SASQueryParameters
-	UnparsedParams      string
-
-	accountName       string // "" if not using IP endpoint style
-	isIPEndpointStyle bool   // Ex: "https://ip/accountname/filesystem"
+	Scheme         string    // Ex: "https://"
+	Host           string    // Ex: "account.file.core.windows.net"
+	ShareName      string    // Share name, Ex: "myshare"
+	DirectoryPath  string    // Path before the last segment, Ex: "mydirectory/mysubdirectory", "" if none
+	FileName       string    // Last path segment, Ex: "myfile.txt"; for a directory URL this is the last directory name unless the path ends in "/"
+	ShareSnapshot  time.Time // IsZero is true if not a snapshot
+	SAS            SASQueryParameters
+	UnparsedParams string
+
+	AccountName string // "" if not using IP endpoint style and Host is a CustomDomain
+	IsSecondary bool   // True if Host points at the account's "-secondary" (RA-GRS) endpoint
+
+	isIPEndpointStyle bool // Ex: "https://ip/accountname/filesystem"
+}
+
+// FileURLPartsOptions augments how NewFileURLPartsWithOptions parses a URL's Host.
+type FileURLPartsOptions struct {
+	// CustomDomain indicates that Host is a customer-mapped domain (Ex: "files.contoso.com") rather than an
+	// "account.file.core.windows.net" or "account-secondary.file.core.windows.net" style host. When true,
+	// AccountName is left empty and IsSecondary is always false.
+	CustomDomain bool
+}
+
+// DirectoryOrFilePath returns the directory and file path joined back into a single "/"-separated string, Ex:
+// "mydirectory/myfile". It exists for backward compatibility with code written against the combined path field.
+func (up FileURLParts) DirectoryOrFilePath() string {
+	switch {
+	case up.DirectoryPath == "":
+		return up.FileName
+	case up.FileName == "":
+		return up.DirectoryPath
+	default:
+		return up.DirectoryPath + "/" + up.FileName
+	}
+}
+
+// splitDirectoryAndFileName splits a directory-or-file path (as found in a Share/Directory/File URL) into its
+// DirectoryPath and FileName parts by walking the last "/"-separated path segment.
+func splitDirectoryAndFileName(path string) (directoryPath string, fileName string) {
+	lastSlash := strings.LastIndex(path, "/")
+	if lastSlash == -1 {
+		return "", path
+	}
+	return path[:lastSlash], path[lastSlash+1:]
 }
 
 // isIPEndpointStyle checkes if URL's host is IP, in this case the storage account endpoint will be composed as:
@@ -40,6 +82,12 @@ func isIPEndpointStyle(url url.URL) bool {
 // NewFileURLParts parses a URL initializing FileURLParts' fields including any SAS-related & sharesnapshot query parameters. Any other
 // query parameters remain in the UnparsedParams field. This method overwrites all fields in the FileURLParts object.
 func NewFileURLParts(u url.URL) FileURLParts {
+	return NewFileURLPartsWithOptions(u, FileURLPartsOptions{})
+}
+
+// NewFileURLPartsWithOptions is like NewFileURLParts but lets the caller indicate that Host is a customer-mapped
+// custom domain rather than an "account[-secondary].file.core.windows.net" style host.
+func NewFileURLPartsWithOptions(u url.URL, o FileURLPartsOptions) FileURLParts {
 	isIPEndpointStyle := isIPEndpointStyle(u)
 	up := FileURLParts{
 		Scheme:            u.Scheme,
@@ -47,6 +95,20 @@ func NewFileURLParts(u url.URL) FileURLParts {
 		isIPEndpointStyle: isIPEndpointStyle,
 	}
 
+	if !isIPEndpointStyle && !o.CustomDomain {
+		// The account name is the leftmost label of the host, Ex: "account" or "account-secondary" in
+		// "account-secondary.file.core.windows.net". Strip the "-secondary" suffix (if any) & record it.
+		accountName := u.Host
+		if dotIndex := strings.Index(accountName, "."); dotIndex != -1 {
+			accountName = accountName[:dotIndex]
+		}
+		if strings.HasSuffix(accountName, secondaryHostSuffix) {
+			up.IsSecondary = true
+			accountName = accountName[:len(accountName)-len(secondaryHostSuffix)]
+		}
+		up.AccountName = accountName
+	}
+
 	if u.Path != "" {
 		path := u.Path
 
@@ -57,9 +119,9 @@ func NewFileURLParts(u url.URL) FileURLParts {
 		if isIPEndpointStyle {
 			accountEndIndex := strings.Index(path, "/")
 			if accountEndIndex == -1 { // Slash not found; path has account name & no share, path of directory or file
-				up.accountName = path
+				up.AccountName = path
 			} else {
-				up.accountName = path[:accountEndIndex] // The account name is the part between the slashes
+				up.AccountName = path[:accountEndIndex] // The account name is the part between the slashes
 
 				path = path[accountEndIndex+1:]
 				// Find the next slash (if it exists)
@@ -68,7 +130,7 @@ func NewFileURLParts(u url.URL) FileURLParts {
 					up.ShareName = path
 				} else { // Slash found; path has share name & path of directory or file
 					up.ShareName = path[:shareEndIndex]
-					up.DirectoryOrFilePath = path[shareEndIndex+1:]
+					up.DirectoryPath, up.FileName = splitDirectoryAndFileName(path[shareEndIndex+1:])
 				}
 			}
 		} else {
@@ -78,7 +140,7 @@ func NewFileURLParts(u url.URL) FileURLParts {
 				up.ShareName = path
 			} else { // Slash found; path has share name & path of directory or file
 				up.ShareName = path[:shareEndIndex]
-				up.DirectoryOrFilePath = path[shareEndIndex+1:]
+				up.DirectoryPath, up.FileName = splitDirectoryAndFileName(path[shareEndIndex+1:])
 			}
 		}
 	}
@@ -87,7 +149,7 @@ func NewFileURLParts(u url.URL) FileURLParts {
 	paramsMap := u.Query()
 
 	if snapshotStr, ok := caseInsensitiveValues(paramsMap).Get(shareSnapshot); ok {
-		up.ShareSnapshot = snapshotStr[0]
+		up.ShareSnapshot, _ = time.Parse(SnapshotTimeFormat, snapshotStr[0])
 		// If we recognized the query parameter, remove it from the map
 		delete(paramsMap, shareSnapshot)
 	}
@@ -112,25 +174,25 @@ func (values caseInsensitiveValues) Get(key string) ([]string, bool) {
 func (up FileURLParts) URL() url.URL {
 	path := ""
 	// Concatenate account name for IP endpoint style URL
-	if up.isIPEndpointStyle && up.accountName != "" {
-		path += "/" + up.accountName
+	if up.isIPEndpointStyle && up.AccountName != "" {
+		path += "/" + up.AccountName
 	}
 	// Concatenate share & path of directory or file (if they exist)
 	if up.ShareName != "" {
 		path += "/" + up.ShareName
-		if up.DirectoryOrFilePath != "" {
-			path += "/" + up.DirectoryOrFilePath
+		if dirOrFilePath := up.DirectoryOrFilePath(); dirOrFilePath != "" {
+			path += "/" + dirOrFilePath
 		}
 	}
 
 	rawQuery := up.UnparsedParams
 
 	// Concatenate share snapshot query parameter (if it exists)
-	if up.ShareSnapshot != "" {
+	if !up.ShareSnapshot.IsZero() {
 		if len(rawQuery) > 0 {
 			rawQuery += "&"
 		}
-		rawQuery += shareSnapshot + "=" + up.ShareSnapshot
+		rawQuery += shareSnapshot + "=" + up.ShareSnapshot.Format(SnapshotTimeFormat)
 	}
 	sas := up.SAS.Encode()
 	if sas != "" {
@@ -0,0 +1,175 @@
+package azfile
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SASTimeFormat represents the format of a SAS start or expiry time. Use it when formatting/parsing a time.Time.
+const SASTimeFormat = "2006-01-02T15:04:05Z" // ISO 8601
+
+// SASProtocol indicates the http/https protocol(s) permitted for a request made with a SAS.
+type SASProtocol string
+
+const (
+	// SASProtocolHTTPS can be specified for a SAS protocol
+	SASProtocolHTTPS SASProtocol = "https"
+
+	// SASProtocolHTTPSandHTTP can be specified for a SAS protocol
+	SASProtocolHTTPSandHTTP SASProtocol = "https,http"
+)
+
+// IPRange represents a range of IP addresses permitted for a request made with a SAS.
+type IPRange struct {
+	Start net.IP // Not specified if len == 0
+	End   net.IP // Not specified if len == 0
+}
+
+// String returns a string representation of an IPRange that can be used for the IP SAS query parameter.
+func (ipr IPRange) String() string {
+	if len(ipr.Start) == 0 {
+		return ""
+	}
+	start := ipr.Start.String()
+	if len(ipr.End) == 0 {
+		return start
+	}
+	return start + "-" + ipr.End.String()
+}
+
+// SASQueryParameters object represents the components that make up an Azure Storage SAS' query parameters.
+// You parse a map of query parameters into its fields by calling newSASQueryParameters(). You add the SAS'
+// query parameters to a URL's query parameters by calling Encode() and appending the result to the URL.
+// NOTE: Changing any field requires computing a new SAS signature with a XxxSASSignatureValues type.
+type SASQueryParameters struct {
+	// All members are immutable or values so copies of this struct are goroutine-safe.
+	Version            string      `param:"sv"`
+	Protocol           SASProtocol `param:"spr"`
+	StartTime          time.Time   `param:"st"`
+	ExpiryTime         time.Time   `param:"se"`
+	Permissions        string      `param:"sp"`
+	IPRange            IPRange     `param:"sip"`
+	Identifier         string      `param:"si"`
+	Resource           string      `param:"sr"`
+	Signature          string      `param:"sig"`
+	CacheControl       string      `param:"rscc"` // rscc is the response header override for Cache-Control
+	ContentDisposition string      `param:"rscd"` // rscd is the response header override for Content-Disposition
+	ContentEncoding    string      `param:"rsce"` // rsce is the response header override for Content-Encoding
+	ContentLanguage    string      `param:"rscl"` // rscl is the response header override for Content-Language
+	ContentType        string      `param:"rsct"` // rsct is the response header override for Content-Type
+}
+
+// WithResponseHeaders returns a copy of this SASQueryParameters with the response header override fields
+// (Cache-Control, Content-Disposition, Content-Encoding, Content-Language & Content-Type) set. The Signature
+// field is copied unchanged and is not recomputed for the new values.
+func (p SASQueryParameters) WithResponseHeaders(cacheControl, contentDisposition, contentEncoding, contentLanguage, contentType string) SASQueryParameters {
+	p.CacheControl = cacheControl
+	p.ContentDisposition = contentDisposition
+	p.ContentEncoding = contentEncoding
+	p.ContentLanguage = contentLanguage
+	p.ContentType = contentType
+	return p
+}
+
+// newSASQueryParameters creates and initializes a SASQueryParameters object based on the query parameter
+// map's passed-in values. If deleteSASParametersFromValues is true, all SAS-related query parameters are
+// removed from the passed-in map. If deleteSASParametersFromValues is false, the passed-in map is unaltered.
+func newSASQueryParameters(values url.Values, deleteSASParametersFromValues bool) SASQueryParameters {
+	p := SASQueryParameters{}
+	for k, v := range values {
+		val := v[0]
+		isSASKey := true
+		switch strings.ToLower(k) {
+		case "sv":
+			p.Version = val
+		case "spr":
+			p.Protocol = SASProtocol(val)
+		case "st":
+			p.StartTime, _ = time.Parse(SASTimeFormat, val)
+		case "se":
+			p.ExpiryTime, _ = time.Parse(SASTimeFormat, val)
+		case "sp":
+			p.Permissions = val
+		case "sip":
+			if dashIndex := strings.Index(val, "-"); dashIndex == -1 {
+				p.IPRange.Start = net.ParseIP(val)
+			} else {
+				p.IPRange.Start = net.ParseIP(val[:dashIndex])
+				p.IPRange.End = net.ParseIP(val[dashIndex+1:])
+			}
+		case "si":
+			p.Identifier = val
+		case "sr":
+			p.Resource = val
+		case "sig":
+			p.Signature = val
+		case "rscc":
+			p.CacheControl = val
+		case "rscd":
+			p.ContentDisposition = val
+		case "rsce":
+			p.ContentEncoding = val
+		case "rscl":
+			p.ContentLanguage = val
+		case "rsct":
+			p.ContentType = val
+		default:
+			isSASKey = false // It's not a SAS parameter we recognize
+		}
+		if isSASKey && deleteSASParametersFromValues {
+			delete(values, k)
+		}
+	}
+	return p
+}
+
+// Encode encodes the SAS query parameters into a URL-encoded string. Parameters are emitted in a fixed,
+// stable order so that two equal SASQueryParameters values always encode to the same string.
+func (p SASQueryParameters) Encode() string {
+	v := url.Values{}
+	if p.Version != "" {
+		v.Add("sv", p.Version)
+	}
+	if p.Protocol != "" {
+		v.Add("spr", string(p.Protocol))
+	}
+	if !p.StartTime.IsZero() {
+		v.Add("st", p.StartTime.Format(SASTimeFormat))
+	}
+	if !p.ExpiryTime.IsZero() {
+		v.Add("se", p.ExpiryTime.Format(SASTimeFormat))
+	}
+	if p.Permissions != "" {
+		v.Add("sp", p.Permissions)
+	}
+	if ipr := p.IPRange.String(); ipr != "" {
+		v.Add("sip", ipr)
+	}
+	if p.Identifier != "" {
+		v.Add("si", p.Identifier)
+	}
+	if p.Resource != "" {
+		v.Add("sr", p.Resource)
+	}
+	if p.CacheControl != "" {
+		v.Add("rscc", p.CacheControl)
+	}
+	if p.ContentDisposition != "" {
+		v.Add("rscd", p.ContentDisposition)
+	}
+	if p.ContentEncoding != "" {
+		v.Add("rsce", p.ContentEncoding)
+	}
+	if p.ContentLanguage != "" {
+		v.Add("rscl", p.ContentLanguage)
+	}
+	if p.ContentType != "" {
+		v.Add("rsct", p.ContentType)
+	}
+	if p.Signature != "" {
+		v.Add("sig", p.Signature)
+	}
+	return strings.Replace(v.Encode(), "+", "%20", -1)
+}
@@ -0,0 +1,197 @@
+package azfile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SASVersion indicates the SAS version that FileSASSignatureValues.NewSASQueryParameters uses when signing
+// a value that did not set an explicit Version.
+const SASVersion = "2017-07-29"
+
+// FileSASSignatureValues is used to generate a Shared Access Signature (SAS) for an Azure Storage share or
+// file. Once all the values here are set appropriately, call NewSASQueryParameters to produce a
+// SASQueryParameters object that can be appended to a FileURLParts' SAS field.
+type FileSASSignatureValues struct {
+	Version     string      // If not specified, defaults to SASVersion
+	Protocol    SASProtocol // Optional
+	StartTime   time.Time   // Optional
+	ExpiryTime  time.Time   // Optional
+	Permissions string      // Create by initializing a ShareSASPermissions or FileSASPermissions and calling String()
+	IPRange     IPRange     // Optional
+	Identifier  string      // Optional, name of a stored access policy that governs Permissions/StartTime/ExpiryTime
+	ShareName   string
+	FilePath    string // Leave "" to create a Share SAS; set to sign a File SAS
+
+	CacheControl       string // Response header override
+	ContentDisposition string // Response header override
+	ContentEncoding    string // Response header override
+	ContentLanguage    string // Response header override
+	ContentType        string // Response header override
+}
+
+// NewSASQueryParameters uses an account's SharedKeyCredential to sign this FileSASSignatureValues, producing
+// the proper SAS query parameters.
+func (v FileSASSignatureValues) NewSASQueryParameters(sharedKeyCredential *SharedKeyCredential) (SASQueryParameters, error) {
+	if sharedKeyCredential == nil {
+		return SASQueryParameters{}, fmt.Errorf("azfile: SharedKeyCredential cannot be nil")
+	}
+	resource := "s"
+	if v.FilePath != "" {
+		resource = "f"
+	}
+	if v.Version == "" {
+		v.Version = SASVersion
+	}
+
+	startTime, expiryTime := formatSASTimeForSigning(v.StartTime), formatSASTimeForSigning(v.ExpiryTime)
+
+	// String-to-sign as defined for a File service SAS.
+	stringToSign := strings.Join([]string{
+		v.Permissions,
+		startTime,
+		expiryTime,
+		v.getCanonicalName(sharedKeyCredential.AccountName()),
+		v.Identifier,
+		v.IPRange.String(),
+		string(v.Protocol),
+		v.Version,
+		v.CacheControl,
+		v.ContentDisposition,
+		v.ContentEncoding,
+		v.ContentLanguage,
+		v.ContentType,
+	}, "\n")
+
+	signature := sharedKeyCredential.computeHMACSHA256(stringToSign)
+
+	p := SASQueryParameters{
+		Version:            v.Version,
+		Protocol:           v.Protocol,
+		StartTime:          v.StartTime,
+		ExpiryTime:         v.ExpiryTime,
+		Permissions:        v.Permissions,
+		IPRange:            v.IPRange,
+		Identifier:         v.Identifier,
+		Resource:           resource,
+		Signature:          signature,
+		CacheControl:       v.CacheControl,
+		ContentDisposition: v.ContentDisposition,
+		ContentEncoding:    v.ContentEncoding,
+		ContentLanguage:    v.ContentLanguage,
+		ContentType:        v.ContentType,
+	}
+	return p, nil
+}
+
+// getCanonicalName computes the canonical name for a share or file resource for SAS signing.
+func (v FileSASSignatureValues) getCanonicalName(accountName string) string {
+	elements := []string{"/file/" + accountName + "/" + v.ShareName}
+	if v.FilePath != "" {
+		elements = append(elements, v.FilePath)
+	}
+	return strings.Join(elements, "/")
+}
+
+// formatSASTimeForSigning formats t for inclusion in a SAS string-to-sign, or returns "" if t is the zero value.
+func formatSASTimeForSigning(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(SASTimeFormat)
+}
+
+// The ShareSASPermissions type simplifies creating the permissions string for an Azure Storage Share SAS.
+// Initialize an instance of this type and then call its String method to set FileSASSignatureValues' Permissions field.
+type ShareSASPermissions struct {
+	Read, Create, Write, Delete, List bool
+}
+
+// String produces the SAS permissions string for an Azure Storage share, using the order the service expects: "rcwdl".
+func (p ShareSASPermissions) String() string {
+	var b bytes.Buffer
+	if p.Read {
+		b.WriteRune('r')
+	}
+	if p.Create {
+		b.WriteRune('c')
+	}
+	if p.Write {
+		b.WriteRune('w')
+	}
+	if p.Delete {
+		b.WriteRune('d')
+	}
+	if p.List {
+		b.WriteRune('l')
+	}
+	return b.String()
+}
+
+// Parse initializes ShareSASPermissions' fields from a string, in any order.
+func (p *ShareSASPermissions) Parse(s string) error {
+	*p = ShareSASPermissions{}
+	for _, r := range s {
+		switch r {
+		case 'r':
+			p.Read = true
+		case 'c':
+			p.Create = true
+		case 'w':
+			p.Write = true
+		case 'd':
+			p.Delete = true
+		case 'l':
+			p.List = true
+		default:
+			return fmt.Errorf("invalid permission character: '%v'", r)
+		}
+	}
+	return nil
+}
+
+// The FileSASPermissions type simplifies creating the permissions string for an Azure Storage File SAS.
+// Initialize an instance of this type and then call its String method to set FileSASSignatureValues' Permissions field.
+type FileSASPermissions struct {
+	Read, Create, Write, Delete bool
+}
+
+// String produces the SAS permissions string for an Azure Storage file, using the order the service expects: "rcwd".
+func (p FileSASPermissions) String() string {
+	var b bytes.Buffer
+	if p.Read {
+		b.WriteRune('r')
+	}
+	if p.Create {
+		b.WriteRune('c')
+	}
+	if p.Write {
+		b.WriteRune('w')
+	}
+	if p.Delete {
+		b.WriteRune('d')
+	}
+	return b.String()
+}
+
+// Parse initializes FileSASPermissions' fields from a string, in any order.
+func (p *FileSASPermissions) Parse(s string) error {
+	*p = FileSASPermissions{}
+	for _, r := range s {
+		switch r {
+		case 'r':
+			p.Read = true
+		case 'c':
+			p.Create = true
+		case 'w':
+			p.Write = true
+		case 'd':
+			p.Delete = true
+		default:
+			return fmt.Errorf("invalid permission character: '%v'", r)
+		}
+	}
+	return nil
+}
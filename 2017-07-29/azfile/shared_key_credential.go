@@ -0,0 +1,35 @@
+package azfile
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// SharedKeyCredential contains an account's name and its primary or secondary key. It is used to compute
+// the HMAC-SHA256 signature of a SAS.
+type SharedKeyCredential struct {
+	accountName string
+	accountKey  []byte
+}
+
+// NewSharedKeyCredential creates a SharedKeyCredential from an account's name and its base64-encoded key.
+func NewSharedKeyCredential(accountName, accountKey string) (*SharedKeyCredential, error) {
+	bytes, err := base64.StdEncoding.DecodeString(accountKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedKeyCredential{accountName: accountName, accountKey: bytes}, nil
+}
+
+// AccountName returns the credential's storage account name.
+func (c *SharedKeyCredential) AccountName() string {
+	return c.accountName
+}
+
+// computeHMACSHA256 generates a base64-encoded HMAC-SHA256 signature of message using the credential's account key.
+func (c *SharedKeyCredential) computeHMACSHA256(message string) string {
+	h := hmac.New(sha256.New, c.accountKey)
+	h.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}